@@ -15,7 +15,6 @@ import (
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/licenses"
-	"golang.org/x/pkgsite/internal/postgres"
 	"golang.org/x/pkgsite/internal/stdlib"
 )
 
@@ -73,6 +72,16 @@ func (s *Server) serveDirectoryPage(ctx context.Context, w http.ResponseWriter,
 	return nil
 }
 
+// packagesInUnitGetter is implemented by the DataSource backends that can
+// list the packages within a unit: *postgres.DB and
+// *proxydatasource.DataSource. It's kept separate from (rather than added
+// to) the internal.DataSource interface itself, which is declared
+// elsewhere, so that adding this capability doesn't require touching that
+// declaration or anything else that depends on it.
+type packagesInUnitGetter interface {
+	GetPackagesInUnit(ctx context.Context, fullPath, modulePath, resolvedVersion string) ([]*internal.PackageMeta, error)
+}
+
 // fetchDirectoryDetails fetches data for the directory specified by path and
 // version from the database and returns a Directory.
 //
@@ -87,14 +96,14 @@ func fetchDirectoryDetails(ctx context.Context, ds internal.DataSource, dmeta *i
 	defer derrors.Wrap(&err, "fetchDirectoryDetails(%q, %q, %q, %v)",
 		dmeta.Path, dmeta.ModulePath, dmeta.Version, dmeta.Licenses)
 
-	db, ok := ds.(*postgres.DB)
+	pig, ok := ds.(packagesInUnitGetter)
 	if !ok {
 		return nil, proxydatasourceNotSupportedErr()
 	}
 	if includeDirPath && dmeta.Path != dmeta.ModulePath && dmeta.Path != stdlib.ModulePath {
 		return nil, fmt.Errorf("includeDirPath can only be set to true if dirPath = modulePath: %w", derrors.InvalidArgument)
 	}
-	packages, err := db.GetPackagesInUnit(ctx, dmeta.Path, dmeta.ModulePath, dmeta.Version)
+	packages, err := pig.GetPackagesInUnit(ctx, dmeta.Path, dmeta.ModulePath, dmeta.Version)
 	if err != nil {
 		if !errors.Is(err, derrors.NotFound) {
 			return nil, err
@@ -0,0 +1,44 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proxydatasource
+
+import (
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/proxy"
+)
+
+// DataSource implements the internal.DataSource interface, by pulling data
+// directly from the module proxy instead of accessing a database.
+type DataSource struct {
+	proxyClient *proxy.Client
+
+	// experiments holds the set of experiments served by GetExperiments. It
+	// defaults to an experimentSource with no loader (so GetExperiments
+	// behaves as it always has: no experiments are active) until
+	// SetExperimentSource is called.
+	experiments *experimentSource
+}
+
+// New creates a new DataSource that reads module data from proxyClient.
+func New(proxyClient *proxy.Client) *DataSource {
+	return &DataSource{
+		proxyClient: proxyClient,
+		experiments: newExperimentSource(nil),
+	}
+}
+
+// SetExperimentSource configures ds to serve GetExperiments from
+// loadExperiments, which is first called by the next GetExperiments and then
+// again by any GetExperiments made more than experimentRefreshInterval after
+// the last successful load, so that a contributor running cmd/pkgsite
+// locally can edit experiment configuration without restarting the server.
+// Use ExperimentsFromFile or ExperimentsFromFlags to build loadExperiments
+// from the local server's --experiment_file or --experiment flags.
+//
+// This is a separate call rather than a New parameter so that existing
+// callers of New are unaffected by DataSource gaining experiment support.
+func (ds *DataSource) SetExperimentSource(loadExperiments func() ([]*internal.Experiment, error)) {
+	ds.experiments = newExperimentSource(loadExperiments)
+}
@@ -42,23 +42,71 @@ func (ds *DataSource) GetLicenses(ctx context.Context, fullPath, modulePath, res
 		return nil, err
 	}
 
-	var lics []*licenses.License
+	lics := licensesForPath(fullPath, modulePath, v.Licenses)
+	if len(lics) == 0 {
+		return nil, fmt.Errorf("path %s is missing from module %s: %w", fullPath, modulePath, derrors.NotFound)
+	}
+	return lics, nil
+}
 
-	// ds.getModule() returns all licenses for the module version. We need to
-	// filter the licenses that applies to the specified fullPath, i.e.
-	// A license in the current or any parent directory of the specified
-	// fullPath applies to it.
-	for _, license := range v.Licenses {
+// licensesForPath returns the licenses in lics that apply to fullPath, i.e. a
+// license in the current or any parent directory of fullPath.
+func licensesForPath(fullPath, modulePath string, lics []*licenses.License) []*licenses.License {
+	var out []*licenses.License
+	for _, license := range lics {
 		licensePath := path.Join(modulePath, path.Dir(license.FilePath))
 		if strings.HasPrefix(fullPath, licensePath) {
-			lics = append(lics, license)
+			out = append(out, license)
 		}
 	}
+	return out
+}
 
-	if len(lics) == 0 {
+// GetPackagesInUnit returns all packages in the unit specified by fullPath,
+// modulePath and resolvedVersion, along with the licenses that apply to each
+// one.
+func (ds *DataSource) GetPackagesInUnit(ctx context.Context, fullPath, modulePath, resolvedVersion string) (_ []*internal.PackageMeta, err error) {
+	defer derrors.Wrap(&err, "GetPackagesInUnit(%q, %q, %q)", fullPath, modulePath, resolvedVersion)
+	m, err := ds.getModule(ctx, modulePath, resolvedVersion)
+	if err != nil {
+		return nil, err
+	}
+	pkgMetas := packagesInUnit(fullPath, modulePath, m)
+	if len(pkgMetas) == 0 {
 		return nil, fmt.Errorf("path %s is missing from module %s: %w", fullPath, modulePath, derrors.NotFound)
 	}
-	return lics, nil
+	return pkgMetas, nil
+}
+
+// packagesInUnit returns PackageMeta for every package in m.Units at or
+// below fullPath, with the licenses that apply to each filled in via
+// licensesForPath.
+func packagesInUnit(fullPath, modulePath string, m *internal.Module) []*internal.PackageMeta {
+	var pkgMetas []*internal.PackageMeta
+	for _, u := range m.Units {
+		if u.Name == "" {
+			// Not a package.
+			continue
+		}
+		if u.Path != fullPath && !strings.HasPrefix(u.Path, fullPath+"/") {
+			continue
+		}
+		var licenseTypes []string
+		for _, lic := range licensesForPath(u.Path, modulePath, m.Licenses) {
+			licenseTypes = append(licenseTypes, lic.Types...)
+		}
+		pm := &internal.PackageMeta{
+			Path:              u.Path,
+			Name:              u.Name,
+			IsRedistributable: u.IsRedistributable,
+			Licenses:          licenseTypes,
+		}
+		if len(u.Documentation) > 0 {
+			pm.Synopsis = u.Documentation[0].Synopsis
+		}
+		pkgMetas = append(pkgMetas, pm)
+	}
+	return pkgMetas
 }
 
 // GetModuleInfo returns the ModuleInfo as fetched from the proxy for module
@@ -103,7 +151,10 @@ func (ds *DataSource) GetPathInfo(ctx context.Context, path, inModulePath, inVer
 	return pi, nil
 }
 
-// GetExperiments is unimplemented.
-func (*DataSource) GetExperiments(ctx context.Context) ([]*internal.Experiment, error) {
-	return nil, nil
+// GetExperiments returns the experiments cached in ds, refreshing them from
+// the source configured by SetExperimentSource if they're stale. See
+// experimentSource for details.
+func (ds *DataSource) GetExperiments(ctx context.Context) (_ []*internal.Experiment, err error) {
+	defer derrors.Wrap(&err, "GetExperiments()")
+	return ds.experiments.get(ctx)
 }
@@ -0,0 +1,103 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proxydatasource
+
+import (
+	"sort"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/licenses"
+)
+
+func TestLicensesForPath(t *testing.T) {
+	rootLicense := &licenses.License{Metadata: &licenses.Metadata{Types: []string{"MIT"}, FilePath: "LICENSE"}}
+	subLicense := &licenses.License{Metadata: &licenses.Metadata{Types: []string{"BSD-3-Clause"}, FilePath: "foo/LICENSE"}}
+	lics := []*licenses.License{rootLicense, subLicense}
+
+	for _, test := range []struct {
+		fullPath string
+		want     []*licenses.License
+	}{
+		{"example.com/mod", []*licenses.License{rootLicense}},
+		{"example.com/mod/foo", []*licenses.License{rootLicense, subLicense}},
+		{"example.com/mod/foo/bar", []*licenses.License{rootLicense, subLicense}},
+	} {
+		got := licensesForPath(test.fullPath, "example.com/mod", lics)
+		if len(got) != len(test.want) {
+			t.Errorf("licensesForPath(%q) = %v, want %v", test.fullPath, got, test.want)
+		}
+	}
+}
+
+func TestPackagesInUnit(t *testing.T) {
+	const modulePath = "example.com/foo"
+	license := &licenses.License{Metadata: &licenses.Metadata{Types: []string{"MIT"}, FilePath: "LICENSE"}}
+	m := &internal.Module{
+		Units: []*internal.Unit{
+			{UnitMeta: internal.UnitMeta{Path: modulePath, Name: "foo"}},
+			{UnitMeta: internal.UnitMeta{Path: modulePath + "/bar", Name: "bar"}},
+			// A directory with no package in it shouldn't be listed.
+			{UnitMeta: internal.UnitMeta{Path: modulePath + "/internal", Name: ""}},
+			// A sibling module that merely shares a path prefix with
+			// modulePath must not be mistaken for a subdirectory of it.
+			{UnitMeta: internal.UnitMeta{Path: "example.com/foobar", Name: "foobar"}},
+		},
+		Licenses: []*licenses.License{license},
+	}
+
+	got := packagesInUnit(modulePath, modulePath, m)
+
+	var gotPaths []string
+	for _, pm := range got {
+		gotPaths = append(gotPaths, pm.Path)
+	}
+	sort.Strings(gotPaths)
+	wantPaths := []string{modulePath, modulePath + "/bar"}
+	if len(gotPaths) != len(wantPaths) {
+		t.Fatalf("packagesInUnit(%q) paths = %v, want %v", modulePath, gotPaths, wantPaths)
+	}
+	for i, p := range gotPaths {
+		if p != wantPaths[i] {
+			t.Errorf("packagesInUnit(%q) paths = %v, want %v", modulePath, gotPaths, wantPaths)
+		}
+	}
+
+	for _, pm := range got {
+		if len(pm.Licenses) == 0 || pm.Licenses[0] != "MIT" {
+			t.Errorf("packagesInUnit(%q): package %s has Licenses = %v, want [MIT] (inherited from the module root license)", modulePath, pm.Path, pm.Licenses)
+		}
+	}
+}
+
+func TestPackagesInUnitSubdirectory(t *testing.T) {
+	const modulePath = "example.com/foo"
+	dirPath := modulePath + "/bar"
+	m := &internal.Module{
+		Units: []*internal.Unit{
+			{UnitMeta: internal.UnitMeta{Path: modulePath, Name: "foo"}},
+			{UnitMeta: internal.UnitMeta{Path: dirPath, Name: "bar"}},
+			{UnitMeta: internal.UnitMeta{Path: dirPath + "baz", Name: "barbaz"}},
+			{UnitMeta: internal.UnitMeta{Path: dirPath + "/baz", Name: "baz"}},
+		},
+	}
+
+	got := packagesInUnit(dirPath, modulePath, m)
+
+	var gotPaths []string
+	for _, pm := range got {
+		gotPaths = append(gotPaths, pm.Path)
+	}
+	sort.Strings(gotPaths)
+	wantPaths := []string{dirPath, dirPath + "/baz"}
+	if len(gotPaths) != len(wantPaths) {
+		t.Fatalf("packagesInUnit(%q) paths = %v, want %v", dirPath, gotPaths, wantPaths)
+	}
+	for i, p := range gotPaths {
+		if p != wantPaths[i] {
+			t.Errorf("packagesInUnit(%q) paths = %v, want %v", dirPath, gotPaths, wantPaths)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proxydatasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// experimentRefreshInterval is how often an experimentSource re-runs its
+// loader. It is short enough that a contributor editing an experiments file
+// on disk sees the change take effect without restarting the server.
+const experimentRefreshInterval = 5 * time.Second
+
+// experimentSource caches the experiments served by DataSource.GetExperiments
+// and refreshes them periodically by calling load. This is the
+// proxydatasource analogue of the experiments table that postgres.DB reads
+// from, for use when there is no database at all.
+type experimentSource struct {
+	load            func() ([]*internal.Experiment, error)
+	refreshInterval time.Duration
+
+	mu          sync.Mutex
+	loaded      bool // whether load has ever succeeded, since experiments may legitimately be nil/empty
+	experiments []*internal.Experiment
+	refreshedAt time.Time
+}
+
+// newExperimentSource returns an experimentSource that calls load to
+// (re)populate its experiments every experimentRefreshInterval. A nil load
+// means no experiments are ever configured.
+func newExperimentSource(load func() ([]*internal.Experiment, error)) *experimentSource {
+	return &experimentSource{load: load, refreshInterval: experimentRefreshInterval}
+}
+
+// get returns the current set of experiments, calling es.load if it has never
+// succeeded or if more than es.refreshInterval has passed since it last
+// succeeded. A nil
+// es is handled directly, rather than left to panic, because DataSource's
+// experiments field is only guaranteed non-nil when the DataSource was built
+// by New.
+func (es *experimentSource) get(ctx context.Context) (_ []*internal.Experiment, err error) {
+	defer derrors.Wrap(&err, "experimentSource.get()")
+	if es == nil || es.load == nil {
+		return nil, nil
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.loaded && time.Since(es.refreshedAt) < es.refreshInterval {
+		return es.experiments, nil
+	}
+	exps, err := es.load()
+	if err != nil {
+		// Keep serving the last good set of experiments rather than letting a
+		// transient load error (e.g. the experiments file briefly unreadable)
+		// turn into an outage of every feature they gate.
+		if es.loaded {
+			return es.experiments, nil
+		}
+		return nil, err
+	}
+	es.experiments = exps
+	es.loaded = true
+	es.refreshedAt = time.Now()
+	return es.experiments, nil
+}
+
+// ExperimentsFromFile returns a loader, suitable for passing to
+// DataSource.SetExperimentSource, that reads a JSON array of experiments
+// from the file at path. This lets contributors running cmd/pkgsite locally
+// against a proxy instance toggle experimental frontend features via an
+// --experiment_file flag, the same way --proxy_url lets them run without a
+// database. (Wiring the flag itself into cmd/pkgsite's main is left to
+// cmd/pkgsite, which is outside this package.)
+//
+// The file should contain JSON of the form:
+//
+//	[
+//		{"name": "retract-tab", "rollout": 100, "description": "show the retractions tab"}
+//	]
+func ExperimentsFromFile(path string) func() ([]*internal.Experiment, error) {
+	return func() (_ []*internal.Experiment, err error) {
+		defer derrors.Wrap(&err, "ExperimentsFromFile(%q)", path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var exps []*internal.Experiment
+		if err := json.Unmarshal(data, &exps); err != nil {
+			return nil, fmt.Errorf("parsing experiments file: %w", err)
+		}
+		return exps, nil
+	}
+}
+
+// ExperimentsFromFlags returns a loader, suitable for passing to
+// DataSource.SetExperimentSource, built from the values of one or more
+// repeated --experiment name=rollout flags to cmd/pkgsite. rollout is
+// optional and defaults to 100 (fully enabled) when omitted.
+func ExperimentsFromFlags(flags []string) func() ([]*internal.Experiment, error) {
+	return func() ([]*internal.Experiment, error) {
+		var exps []*internal.Experiment
+		for _, f := range flags {
+			parts := strings.SplitN(f, "=", 2)
+			name := strings.TrimSpace(parts[0])
+			if name == "" {
+				return nil, fmt.Errorf("invalid --experiment flag %q: missing experiment name", f)
+			}
+			rollout := uint(100)
+			if len(parts) == 2 && parts[1] != "" {
+				r, err := strconv.ParseUint(parts[1], 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid --experiment flag %q: rollout must be a non-negative integer: %w", f, err)
+				}
+				rollout = uint(r)
+			}
+			exps = append(exps, &internal.Experiment{Name: name, Rollout: rollout})
+		}
+		return exps, nil
+	}
+}
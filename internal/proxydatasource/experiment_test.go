@@ -0,0 +1,191 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proxydatasource
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestExperimentSourceGetCachesWithinRefreshInterval(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	es := newExperimentSource(func() ([]*internal.Experiment, error) {
+		calls++
+		return []*internal.Experiment{{Name: "a", Rollout: 100}}, nil
+	})
+	es.refreshInterval = time.Hour
+
+	if _, err := es.get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := es.get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("load called %d times within refreshInterval, want 1", calls)
+	}
+
+	es.refreshedAt = time.Now().Add(-2 * time.Hour)
+	if _, err := es.get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("load called %d times after refreshInterval elapsed, want 2", calls)
+	}
+}
+
+func TestExperimentSourceGetCachesEmptyResult(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	es := newExperimentSource(func() ([]*internal.Experiment, error) {
+		calls++
+		return nil, nil // e.g. ExperimentsFromFlags(nil): no flags set, no error.
+	})
+	es.refreshInterval = time.Hour
+
+	if _, err := es.get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := es.get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("load called %d times within refreshInterval for an empty result, want 1", calls)
+	}
+}
+
+func TestExperimentSourceGetServesStaleOnReloadError(t *testing.T) {
+	ctx := context.Background()
+	good := []*internal.Experiment{{Name: "a", Rollout: 100}}
+	fail := true
+	es := newExperimentSource(func() ([]*internal.Experiment, error) {
+		if fail {
+			return nil, errors.New("transient failure")
+		}
+		return good, nil
+	})
+	es.refreshInterval = time.Hour
+
+	fail = false
+	got, err := es.get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "a" {
+		t.Fatalf("get() = %v, want the loaded experiment", got)
+	}
+
+	fail = true
+	es.refreshedAt = time.Now().Add(-2 * time.Hour)
+	got, err = es.get(ctx)
+	if err != nil {
+		t.Fatalf("get() after a failing reload returned an error, want the stale cache: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "a" {
+		t.Errorf("get() = %v, want the previously cached experiment", got)
+	}
+}
+
+func TestExperimentSourceGetErrorsWithNoCache(t *testing.T) {
+	ctx := context.Background()
+	wantErr := errors.New("no experiments available")
+	es := newExperimentSource(func() ([]*internal.Experiment, error) {
+		return nil, wantErr
+	})
+
+	if _, err := es.get(ctx); err == nil {
+		t.Fatal("get() = nil error, want an error since there is no cached fallback")
+	}
+}
+
+func TestExperimentsFromFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid", func(t *testing.T) {
+		path := filepath.Join(dir, "experiments.json")
+		if err := os.WriteFile(path, []byte(`[{"name": "retract-tab", "rollout": 100}]`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		exps, err := ExperimentsFromFile(path)()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(exps) != 1 || exps[0].Name != "retract-tab" || exps[0].Rollout != 100 {
+			t.Errorf("ExperimentsFromFile(%q)() = %v, want one retract-tab experiment", path, exps)
+		}
+	})
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := ExperimentsFromFile(filepath.Join(dir, "does-not-exist.json"))(); err == nil {
+			t.Error("ExperimentsFromFile for a missing file returned no error, want one")
+		}
+	})
+	t.Run("malformed json", func(t *testing.T) {
+		path := filepath.Join(dir, "malformed.json")
+		if err := os.WriteFile(path, []byte(`not json`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ExperimentsFromFile(path)(); err == nil {
+			t.Error("ExperimentsFromFile for a malformed file returned no error, want one")
+		}
+	})
+}
+
+func TestExperimentsFromFlags(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		flags   []string
+		want    []*internal.Experiment
+		wantErr bool
+	}{
+		{
+			name:  "name only defaults rollout to 100",
+			flags: []string{"retract-tab"},
+			want:  []*internal.Experiment{{Name: "retract-tab", Rollout: 100}},
+		},
+		{
+			name:  "name and rollout",
+			flags: []string{"retract-tab=50"},
+			want:  []*internal.Experiment{{Name: "retract-tab", Rollout: 50}},
+		},
+		{
+			name:    "missing name",
+			flags:   []string{"=50"},
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric rollout",
+			flags:   []string{"retract-tab=soon"},
+			wantErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ExperimentsFromFlags(test.flags)()
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ExperimentsFromFlags(%v)() = %v, nil, want an error", test.flags, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("ExperimentsFromFlags(%v)() = %v, want %v", test.flags, got, test.want)
+			}
+			for i, g := range got {
+				if *g != *test.want[i] {
+					t.Errorf("ExperimentsFromFlags(%v)()[%d] = %+v, want %+v", test.flags, i, *g, *test.want[i])
+				}
+			}
+		})
+	}
+}